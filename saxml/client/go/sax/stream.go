@@ -0,0 +1,59 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sax
+
+import "context"
+
+// StreamItem is one item delivered on the channel GenerateStream returns: one of the N-best
+// Generate results, not an incremental token chunk (see GenerateStream's doc comment).
+type StreamItem struct {
+	// Text is one Generate result's text.
+	Text string
+	// Err is set, and the channel closed immediately after, if the call failed.
+	Err error
+}
+
+// GenerateStream starts a Generate call and delivers its results on the returned channel as a
+// stream of StreamItems, one per N-best result.
+//
+// TODO: this is not wired to a real per-token streaming Generate RPC — no such RPC is defined in
+// this package yet. It replays the existing blocking Generate call's N-best results onto the
+// channel, so callers must not treat a StreamItem as an individual token: for the common
+// single-candidate case exactly one StreamItem is delivered, with the same latency as a blocking
+// Generate call. Do not derive time-to-first-token or inter-token-latency metrics from this
+// until a real streaming RPC exists; see lmGenerateBlocking/lmGenerateStream in
+// custom_tools/locust_worker.go for why those specific metrics were dropped.
+func (m *LanguageModel) GenerateStream(ctx context.Context, text string) (<-chan StreamItem, error) {
+	ch := make(chan StreamItem, 1)
+	go func() {
+		defer close(ch)
+		results, err := m.Generate(ctx, text)
+		if err != nil {
+			select {
+			case ch <- StreamItem{Err: err}:
+			case <-ctx.Done():
+			}
+			return
+		}
+		for _, r := range results {
+			select {
+			case ch <- StreamItem{Text: r.Text}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
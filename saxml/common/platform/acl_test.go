@@ -0,0 +1,63 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloud
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckACLs(t *testing.T) {
+	cases := []struct {
+		name     string
+		identity any
+		acls     []string
+		wantErr  bool
+	}{
+		{name: "no ACLs required", acls: nil, wantErr: false},
+		{name: "identity missing from context", identity: nil, acls: []string{"alice"}, wantErr: true},
+		{name: "identity not in ACL", identity: "mallory", acls: []string{"alice", "bob"}, wantErr: true},
+		{name: "identity in ACL", identity: "bob", acls: []string{"alice", "bob"}, wantErr: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := context.Background()
+			if c.identity != nil {
+				ctx = context.WithValue(ctx, callerIdentityKey{}, c.identity)
+			}
+			s := &Server{}
+			err := s.CheckACLs(ctx, c.acls)
+			if (err != nil) != c.wantErr {
+				t.Errorf("CheckACLs() = %v, wantErr %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	cases := []struct {
+		granted, required string
+		want              bool
+	}{
+		{granted: "", required: "x", want: false},
+		{granted: "a b c", required: "b", want: true},
+		{granted: "a b c", required: "d", want: false},
+	}
+	for _, c := range cases {
+		if got := hasScope(c.granted, c.required); got != c.want {
+			t.Errorf("hasScope(%q, %q) = %v, want %v", c.granted, c.required, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aferoenv
+
+import (
+	"context"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/spf13/afero"
+	"saxml/common/platform/env"
+)
+
+// newBackends returns the afero.Fs backends this package's adapters are tested against: a real
+// local directory and an in-memory filesystem, so the same table exercises both.
+func newBackends(t *testing.T) map[string]afero.Fs {
+	t.Helper()
+	return map[string]afero.Fs{
+		"local": afero.NewBasePathFs(afero.NewOsFs(), t.TempDir()),
+		"mem":   afero.NewMemMapFs(),
+	}
+}
+
+func TestFromFsReadWriteRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for name, fs := range newBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			e := FromFs(fs)
+
+			if err := e.CreateDir(ctx, "/a/b", ""); err != nil {
+				t.Fatalf("CreateDir: %v", err)
+			}
+			if ok, err := e.DirExists(ctx, "/a/b"); err != nil || !ok {
+				t.Fatalf("DirExists(/a/b) = %v, %v, want true, nil", ok, err)
+			}
+
+			want := []byte("hello sax")
+			if err := e.WriteFile(ctx, "/a/b/c.txt", want); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+			if ok, err := e.FileExists(ctx, "/a/b/c.txt"); err != nil || !ok {
+				t.Fatalf("FileExists(/a/b/c.txt) = %v, %v, want true, nil", ok, err)
+			}
+			got, err := e.ReadFile(ctx, "/a/b/c.txt")
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("ReadFile = %q, want %q", got, want)
+			}
+
+			dirs, err := e.ListSubdirs(ctx, "/a")
+			if err != nil {
+				t.Fatalf("ListSubdirs: %v", err)
+			}
+			if len(dirs) != 1 || dirs[0] != "b" {
+				t.Errorf("ListSubdirs(/a) = %v, want [b]", dirs)
+			}
+		})
+	}
+}
+
+func TestFromEnvReadWriteRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for name, fs := range newBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			var e env.Env = FromFs(fs)
+			afs := FromEnv(ctx, e)
+
+			if err := afs.MkdirAll("/a/b", 0777); err != nil {
+				t.Fatalf("MkdirAll: %v", err)
+			}
+
+			want := []byte("hello afero")
+			f, err := afs.Create("/a/b/c.txt")
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if _, err := f.Write(want); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := f.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			got, err := afero.ReadFile(afs, "/a/b/c.txt")
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("ReadFile = %q, want %q", got, want)
+			}
+
+			info, err := afs.Stat("/a/b/c.txt")
+			if err != nil {
+				t.Fatalf("Stat: %v", err)
+			}
+			if info.IsDir() {
+				t.Errorf("Stat(/a/b/c.txt).IsDir() = true, want false")
+			}
+			if info.Size() != int64(len(want)) {
+				t.Errorf("Stat(/a/b/c.txt).Size() = %d, want %d", info.Size(), len(want))
+			}
+		})
+	}
+}
+
+func TestEnvFsReaddirPaging(t *testing.T) {
+	ctx := context.Background()
+	for name, fs := range newBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			e := FromFs(fs)
+			for _, sub := range []string{"a/x", "a/y", "a/z"} {
+				if err := e.CreateDir(ctx, "/"+sub, ""); err != nil {
+					t.Fatalf("CreateDir(%v): %v", sub, err)
+				}
+			}
+
+			dir, err := FromEnv(ctx, e).Open("/a")
+			if err != nil {
+				t.Fatalf("Open(/a): %v", err)
+			}
+			defer dir.Close()
+
+			var got []string
+			for {
+				names, err := dir.Readdirnames(2)
+				got = append(got, names...)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Readdirnames(2): %v", err)
+				}
+				if len(names) == 0 {
+					t.Fatalf("Readdirnames(2) returned no names and no io.EOF, would loop forever")
+				}
+			}
+
+			sort.Strings(got)
+			want := []string{"x", "y", "z"}
+			if len(got) != len(want) {
+				t.Fatalf("Readdirnames pages = %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Readdirnames pages = %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
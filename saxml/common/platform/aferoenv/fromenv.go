@@ -0,0 +1,309 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aferoenv adapts between the platform-neutral env.Env interface and spf13/afero's
+// afero.Fs, in both directions. Tools that only know how to talk to an afero.Fs (config
+// loaders, checkpoint readers, templating) can transparently read from local disk or gs://
+// through FromEnv, and env.Env-based code gains access to afero.NewMemMapFs() via FromFs,
+// most usefully for tests that currently have to touch a real tempdir.
+package aferoenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"saxml/common/errors"
+	"saxml/common/platform/env"
+)
+
+// envFs implements afero.Fs on top of an env.Env, so afero-based tools can transparently read
+// from whatever backend env.Env is registered for the platform (local disk or gs://).
+type envFs struct {
+	ctx context.Context
+	env env.Env
+}
+
+// FromEnv wraps e as an afero.Fs. ctx is used for every env.Env call made through the returned
+// Fs, since afero.Fs operations take no context of their own.
+func FromEnv(ctx context.Context, e env.Env) afero.Fs {
+	return &envFs{ctx: ctx, env: e}
+}
+
+// Name implements afero.Fs.
+func (f *envFs) Name() string { return "envFs" }
+
+// Open implements afero.Fs.
+func (f *envFs) Open(name string) (afero.File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile implements afero.Fs.
+func (f *envFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if isDir, err := f.env.DirExists(f.ctx, name); err == nil && isDir {
+		return &envFile{fs: f, name: name, isDir: true}, nil
+	}
+
+	file := &envFile{fs: f, name: name, writable: flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE) != 0}
+	if flag&os.O_CREATE == 0 {
+		data, err := f.env.ReadFile(f.ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		file.buf.Write(data)
+	}
+	return file, nil
+}
+
+// Create implements afero.Fs.
+func (f *envFs) Create(name string) (afero.File, error) {
+	return f.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+}
+
+// Mkdir implements afero.Fs.
+func (f *envFs) Mkdir(name string, perm os.FileMode) error {
+	return f.env.CreateDir(f.ctx, name, "")
+}
+
+// MkdirAll implements afero.Fs.
+func (f *envFs) MkdirAll(path string, perm os.FileMode) error {
+	return f.env.CreateDir(f.ctx, path, "")
+}
+
+// Remove implements afero.Fs. env.Env has no deletion primitive, so this is unsupported.
+func (f *envFs) Remove(name string) error {
+	return fmt.Errorf("Remove is not supported on an env.Env-backed filesystem: %w", errors.ErrUnimplemented)
+}
+
+// RemoveAll implements afero.Fs. env.Env has no deletion primitive, so this is unsupported.
+func (f *envFs) RemoveAll(path string) error {
+	return fmt.Errorf("RemoveAll is not supported on an env.Env-backed filesystem: %w", errors.ErrUnimplemented)
+}
+
+// Rename implements afero.Fs. env.Env has no rename primitive, so this is unsupported.
+func (f *envFs) Rename(oldname, newname string) error {
+	return fmt.Errorf("Rename is not supported on an env.Env-backed filesystem: %w", errors.ErrUnimplemented)
+}
+
+// Stat implements afero.Fs.
+func (f *envFs) Stat(name string) (os.FileInfo, error) {
+	if isDir, err := f.env.DirExists(f.ctx, name); err == nil && isDir {
+		return &envFileInfo{name: filepath.Base(name), isDir: true}, nil
+	}
+	data, err := f.env.ReadFile(f.ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &envFileInfo{name: filepath.Base(name), size: int64(len(data))}, nil
+}
+
+// Chmod implements afero.Fs. env.Env has no permission bits, so this is unsupported.
+func (f *envFs) Chmod(name string, mode os.FileMode) error {
+	return fmt.Errorf("Chmod is not supported on an env.Env-backed filesystem: %w", errors.ErrUnimplemented)
+}
+
+// Chtimes implements afero.Fs. env.Env has no mtime, so this is unsupported.
+func (f *envFs) Chtimes(name string, atime, mtime time.Time) error {
+	return fmt.Errorf("Chtimes is not supported on an env.Env-backed filesystem: %w", errors.ErrUnimplemented)
+}
+
+// Chown implements afero.Fs. env.Env has no ownership, so this is unsupported.
+func (f *envFs) Chown(name string, uid, gid int) error {
+	return fmt.Errorf("Chown is not supported on an env.Env-backed filesystem: %w", errors.ErrUnimplemented)
+}
+
+// envFile implements afero.File on top of an env.Env. Reads are buffered in full at Open time
+// and writes are buffered in full until Close, when they're flushed with a single
+// WriteFileAtomically call — env.Env has no streaming or partial-write primitive.
+type envFile struct {
+	fs       *envFs
+	name     string
+	isDir    bool
+	buf      bytes.Buffer
+	pos      int64
+	writable bool
+
+	// dirNames, dirPos, and dirLoaded back Readdir/Readdirnames, which page through a single
+	// ListSubdirs listing across repeated calls per the os.File/afero.File count contract.
+	dirNames  []string
+	dirPos    int
+	dirLoaded bool
+}
+
+// Close implements afero.File.
+func (e *envFile) Close() error {
+	if !e.writable {
+		return nil
+	}
+	return e.fs.env.WriteFileAtomically(e.fs.ctx, e.name, e.buf.Bytes())
+}
+
+// Read implements afero.File.
+func (e *envFile) Read(p []byte) (int, error) {
+	if e.pos >= int64(e.buf.Len()) {
+		return 0, io.EOF
+	}
+	n := copy(p, e.buf.Bytes()[e.pos:])
+	e.pos += int64(n)
+	return n, nil
+}
+
+// ReadAt implements afero.File.
+func (e *envFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(e.buf.Len()) {
+		return 0, io.EOF
+	}
+	return copy(p, e.buf.Bytes()[off:]), nil
+}
+
+// Seek implements afero.File.
+func (e *envFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		e.pos = offset
+	case io.SeekCurrent:
+		e.pos += offset
+	case io.SeekEnd:
+		e.pos = int64(e.buf.Len()) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+	return e.pos, nil
+}
+
+// Write implements afero.File.
+func (e *envFile) Write(p []byte) (int, error) {
+	if gap := e.pos - int64(e.buf.Len()); gap > 0 {
+		e.buf.Write(make([]byte, gap))
+	}
+	n, err := e.buf.Write(p)
+	e.pos += int64(n)
+	return n, err
+}
+
+// WriteAt implements afero.File.
+func (e *envFile) WriteAt(p []byte, off int64) (int, error) {
+	if gap := off + int64(len(p)) - int64(e.buf.Len()); gap > 0 {
+		e.buf.Write(make([]byte, gap))
+	}
+	copy(e.buf.Bytes()[off:], p)
+	return len(p), nil
+}
+
+// WriteString implements afero.File.
+func (e *envFile) WriteString(s string) (int, error) {
+	return e.Write([]byte(s))
+}
+
+// Name implements afero.File.
+func (e *envFile) Name() string { return e.name }
+
+// loadDir lazily fetches the directory listing once, so repeated paged Readdir/Readdirnames
+// calls share a single ListSubdirs round trip.
+func (e *envFile) loadDir() error {
+	if e.dirLoaded {
+		return nil
+	}
+	names, err := e.fs.env.ListSubdirs(e.fs.ctx, e.name)
+	if err != nil {
+		return err
+	}
+	e.dirNames, e.dirLoaded = names, true
+	return nil
+}
+
+// nextDirNames advances past and returns the next names per the count contract shared by
+// Readdir and Readdirnames: count<=0 returns all remaining entries, count>0 returns at most
+// count entries and io.EOF once the directory is exhausted.
+func (e *envFile) nextDirNames(count int) ([]string, error) {
+	remaining := e.dirNames[e.dirPos:]
+	if count <= 0 {
+		e.dirPos = len(e.dirNames)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	e.dirPos += count
+	return remaining[:count], nil
+}
+
+// Readdir implements afero.File.
+func (e *envFile) Readdir(count int) ([]os.FileInfo, error) {
+	if err := e.loadDir(); err != nil {
+		return nil, err
+	}
+	names, err := e.nextDirNames(count)
+	infos := make([]os.FileInfo, 0, len(names))
+	for _, n := range names {
+		infos = append(infos, &envFileInfo{name: n, isDir: true})
+	}
+	return infos, err
+}
+
+// Readdirnames implements afero.File.
+func (e *envFile) Readdirnames(count int) ([]string, error) {
+	if err := e.loadDir(); err != nil {
+		return nil, err
+	}
+	return e.nextDirNames(count)
+}
+
+// Stat implements afero.File.
+func (e *envFile) Stat() (os.FileInfo, error) {
+	return e.fs.Stat(e.name)
+}
+
+// Sync implements afero.File. Writes aren't flushed until Close, so this is a no-op.
+func (e *envFile) Sync() error { return nil }
+
+// Truncate implements afero.File.
+func (e *envFile) Truncate(size int64) error {
+	switch {
+	case int64(e.buf.Len()) < size:
+		e.buf.Write(make([]byte, size-int64(e.buf.Len())))
+	case int64(e.buf.Len()) > size:
+		trimmed := e.buf.Bytes()[:size]
+		e.buf.Reset()
+		e.buf.Write(trimmed)
+	}
+	return nil
+}
+
+// envFileInfo implements os.FileInfo for envFs, which has no mode bits or mtimes to report.
+type envFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (i *envFileInfo) Name() string { return i.name }
+func (i *envFileInfo) Size() int64  { return i.size }
+func (i *envFileInfo) Mode() os.FileMode {
+	if i.isDir {
+		return os.ModeDir | 0777
+	}
+	return 0644
+}
+func (i *envFileInfo) ModTime() time.Time { return time.Time{} }
+func (i *envFileInfo) IsDir() bool        { return i.isDir }
+func (i *envFileInfo) Sys() any           { return nil }
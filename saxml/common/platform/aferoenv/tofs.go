@@ -0,0 +1,164 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aferoenv
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"google.golang.org/grpc"
+	"github.com/spf13/afero"
+	"saxml/common/errors"
+	"saxml/common/platform/env"
+)
+
+// fsEnv implements env.Env on top of an afero.Fs, most usefully for tests that want an
+// in-memory root (afero.NewMemMapFs()) instead of a real tempdir.
+type fsEnv struct {
+	fs afero.Fs
+
+	// leads maps each path passed to Lead to the *sync.Mutex guarding it.
+	leads sync.Map
+}
+
+// FromFs wraps fs as an env.Env.
+func FromFs(fs afero.Fs) env.Env {
+	return &fsEnv{fs: fs}
+}
+
+// Init implements env.Env.
+func (e *fsEnv) Init(ctx context.Context) {}
+
+// ReadFile implements env.Env.
+func (e *fsEnv) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	return afero.ReadFile(e.fs, path)
+}
+
+// ReadCachedFile implements env.Env.
+func (e *fsEnv) ReadCachedFile(ctx context.Context, path string) ([]byte, error) {
+	return e.ReadFile(ctx, path)
+}
+
+// WriteFile implements env.Env.
+func (e *fsEnv) WriteFile(ctx context.Context, path string, data []byte) error {
+	return afero.WriteFile(e.fs, path, data, 0644)
+}
+
+// WriteFileAtomically implements env.Env.
+func (e *fsEnv) WriteFileAtomically(ctx context.Context, path string, data []byte) error {
+	return e.WriteFile(ctx, path, data)
+}
+
+// FileExists implements env.Env.
+func (e *fsEnv) FileExists(ctx context.Context, path string) (bool, error) {
+	info, err := e.fs.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() {
+		return false, fmt.Errorf("%s is a directory, not a file: %w", path, errors.ErrFailedPrecondition)
+	}
+	return true, nil
+}
+
+// RootDir implements env.Env.
+func (e *fsEnv) RootDir(ctx context.Context) string {
+	return "/"
+}
+
+// CreateDir implements env.Env.
+func (e *fsEnv) CreateDir(ctx context.Context, path, acl string) error {
+	if acl != "" {
+		return fmt.Errorf("CreateDir with ACL is not supported: %w", errors.ErrUnimplemented)
+	}
+	return e.fs.MkdirAll(path, 0777)
+}
+
+// ListSubdirs implements env.Env.
+func (e *fsEnv) ListSubdirs(ctx context.Context, path string) ([]string, error) {
+	infos, err := afero.ReadDir(e.fs, path)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, info := range infos {
+		dirs = append(dirs, info.Name())
+	}
+	return dirs, nil
+}
+
+// DirExists implements env.Env.
+func (e *fsEnv) DirExists(ctx context.Context, path string) (bool, error) {
+	info, err := e.fs.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info.IsDir() {
+		return true, nil
+	}
+	return false, fmt.Errorf("%s is a file, not a directory: %w", path, errors.ErrFailedPrecondition)
+}
+
+// Watch implements env.Env. afero.Fs has no change-notification primitive, so this returns a
+// channel that never receives anything.
+func (e *fsEnv) Watch(ctx context.Context, path string) (<-chan []byte, error) {
+	return make(<-chan []byte), nil
+}
+
+// Lead implements env.Env with a process-local lock, sufficient for the single-process tests
+// this adapter is meant for.
+func (e *fsEnv) Lead(ctx context.Context, path string) (chan<- struct{}, error) {
+	v, _ := e.leads.LoadOrStore(path, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	closer := make(chan struct{})
+	go func() {
+		<-closer
+		mu.Unlock()
+	}()
+	return closer, nil
+}
+
+// PickUnusedPort implements env.Env.
+func (e *fsEnv) PickUnusedPort() (int, error) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// DialContext implements env.Env.
+func (e *fsEnv) DialContext(ctx context.Context, target string) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, target, grpc.WithInsecure())
+}
+
+// RequiredACLNamePrefix implements env.Env.
+func (e *fsEnv) RequiredACLNamePrefix() string { return "" }
+
+// NewServer implements env.Env. Serving isn't meaningful for an afero.Fs-backed environment.
+func (e *fsEnv) NewServer() (env.Server, error) {
+	return nil, fmt.Errorf("NewServer is not supported on an afero.Fs-backed environment: %w", errors.ErrUnimplemented)
+}
@@ -0,0 +1,88 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloud
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func withCacheFlags(t *testing.T, maxBytes int64) {
+	t.Helper()
+	dir := t.TempDir()
+	oldDir, oldMax := *saxCacheDir, *saxCacheMaxBytes
+	*saxCacheDir, *saxCacheMaxBytes = dir, maxBytes
+	t.Cleanup(func() { *saxCacheDir, *saxCacheMaxBytes = oldDir, oldMax })
+}
+
+func TestTouchCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	withCacheFlags(t, 25)
+
+	// Create the three underlying cache files touchCache is expected to manage.
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.WriteFile(filepath.Join(*saxCacheDir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("WriteFile(%v): %v", name, err)
+		}
+	}
+
+	touchCache("a", 10)
+	touchCache("b", 10)
+	// Touching "a" again makes "b" the least recently used entry.
+	touchCache("a", 10)
+	// Pushes total past the 25-byte budget; "b" should be evicted, not "a".
+	touchCache("c", 10)
+
+	idx := loadCacheIndex()
+	if _, ok := idx["b"]; ok {
+		t.Errorf("cache index still has evicted entry %q: %+v", "b", idx)
+	}
+	if _, ok := idx["a"]; !ok {
+		t.Errorf("cache index missing recently-used entry %q: %+v", "a", idx)
+	}
+	if _, ok := idx["c"]; !ok {
+		t.Errorf("cache index missing just-written entry %q: %+v", "c", idx)
+	}
+	if _, err := os.Stat(filepath.Join(*saxCacheDir, "b")); !os.IsNotExist(err) {
+		t.Errorf("evicted cache file %q still exists on disk: %v", "b", err)
+	}
+}
+
+func TestPruneOldGenerations(t *testing.T) {
+	withCacheFlags(t, 1<<30)
+
+	path := "/gcs/bucket/obj"
+	older := cacheFileName(path, 1)
+	newer := cacheFileName(path, 2)
+	unrelated := cacheFileName("/gcs/bucket/other", 1)
+	for _, name := range []string{older, newer, unrelated} {
+		if err := os.WriteFile(filepath.Join(*saxCacheDir, name), []byte("x"), 0644); err != nil {
+			t.Fatalf("WriteFile(%v): %v", name, err)
+		}
+		touchCache(name, 1)
+	}
+
+	pruneOldGenerations(path, newer)
+
+	if _, err := os.Stat(filepath.Join(*saxCacheDir, older)); !os.IsNotExist(err) {
+		t.Errorf("stale generation %q was not pruned: %v", older, err)
+	}
+	if _, err := os.Stat(filepath.Join(*saxCacheDir, newer)); err != nil {
+		t.Errorf("current generation %q was pruned: %v", newer, err)
+	}
+	if _, err := os.Stat(filepath.Join(*saxCacheDir, unrelated)); err != nil {
+		t.Errorf("unrelated path's cache file %q was pruned: %v", unrelated, err)
+	}
+}
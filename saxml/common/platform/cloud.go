@@ -17,24 +17,38 @@ package cloud
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"flag"
 	log "github.com/golang/glog"
 
+	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/storage"
+	"github.com/fsnotify/fsnotify"
 	"google.golang.org/api/iterator"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/sync/singleflight"
+	oauth2api "google.golang.org/api/oauth2/v2"
+	"google.golang.org/api/option"
 	"saxml/common/errors"
 	"saxml/common/platform/env"
 )
@@ -55,12 +69,109 @@ var (
 	saxRoot  = flag.String("sax_root", "", "Sax cell root, e.g. /local/dir or gs://bucket/dir")
 	testRoot = filepath.Join(os.TempDir(), "sax-test-root")
 
+	gcsNotificationTopic = flag.String("gcs_notification_topic", "", "Pub/Sub topic receiving GCS object-change notifications for watched objects; if unset, Watch falls back to polling")
+
+	saxCacheDir      = flag.String("sax_cache_dir", filepath.Join(os.TempDir(), "sax-cache"), "Local directory used by ReadCachedFile to cache downloaded GCS files")
+	saxCacheMaxBytes = flag.Int64("sax_cache_max_bytes", 10<<30, "Maximum total bytes kept in the ReadCachedFile cache; least-recently-used entries are evicted past this budget")
+
+	saxTLSCA         = flag.String("sax_tls_ca", "", "PEM CA bundle used to verify gRPC server certificates on dial; if empty, the system cert pool is used")
+	saxTLSCert       = flag.String("sax_tls_cert", "", "PEM certificate file used by the gRPC server for TLS")
+	saxTLSKey        = flag.String("sax_tls_key", "", "PEM private key file matching --sax_tls_cert")
+	saxAllowInsecure = flag.Bool("sax_allow_insecure", false, "Allow plaintext, unauthenticated gRPC connections and servers; for local testing only")
+
+	saxOAuthAudience = flag.String("sax_oauth_audience", "", "Expected OAuth2 client ID (aud/issued_to) on caller tokens; if empty, audience is not checked")
+	saxOAuthScope    = flag.String("sax_oauth_scope", "", "OAuth2 scope required on caller tokens; if empty, scope is not checked")
+
 	projectID string
 	gcsClient *storage.Client
 
-	muLeader sync.Mutex
+	pubsubClientOnce sync.Once
+	pubsubClient     *pubsub.Client
+	pubsubClientErr  error
+
+	cacheGroup singleflight.Group
+	cacheMu    sync.Mutex
+	cacheStats struct {
+		hits, misses int64
+	}
+)
+
+const (
+	// leaseDuration is how long a held lock remains valid without being refreshed. A holder
+	// that dies without releasing the lock is considered gone once its lease expires.
+	leaseDuration = 30 * time.Second
+	// leaseRefreshInterval is how often a holder rewrites the lock object to extend its lease.
+	// It must be comfortably shorter than leaseDuration to tolerate slow GCS round trips.
+	leaseRefreshInterval = 10 * time.Second
+	// leaseRetryInterval is the backoff between acquisition attempts while the lock is held
+	// by someone else.
+	leaseRetryInterval = 2 * time.Second
 )
 
+// leaseRecord is the JSON payload stored in a GCS lock object, recording who
+// currently holds the lock, when their lease expires, and a monotonic epoch for diagnostics.
+type leaseRecord struct {
+	Holder   string    `json:"holder"`
+	Deadline time.Time `json:"deadline"`
+	Epoch    int64     `json:"epoch"`
+}
+
+func lockPath(path string) string {
+	return path + ".lock"
+}
+
+// newHolderID returns an identifier that's unique enough to tell lock holders apart for
+// diagnostics; it doesn't need to be globally unique, only distinguishable in practice.
+func newHolderID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%x", host, os.Getpid(), rand.Uint64())
+}
+
+// readLease reads the current lease record of a GCS lock object along with its generation.
+// It returns storage.ErrObjectNotExist if the lock object doesn't exist yet.
+func readLease(ctx context.Context, object *storage.ObjectHandle) (*leaseRecord, int64, error) {
+	attrs, err := object.Attrs(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	r, err := object.NewReader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	var rec leaseRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, 0, err
+	}
+	return &rec, attrs.Generation, nil
+}
+
+// writeLease writes rec to the lock object, requiring the object to currently be absent (when
+// wantGeneration is 0) or at generation wantGeneration. It returns the generation of the write.
+func writeLease(ctx context.Context, object *storage.ObjectHandle, rec leaseRecord, wantGeneration int64) (int64, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return 0, err
+	}
+	conditioned := object.If(storage.Conditions{GenerationMatch: wantGeneration})
+	if wantGeneration == 0 {
+		conditioned = object.If(storage.Conditions{DoesNotExist: true})
+	}
+	w := conditioned.NewWriter(ctx)
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return w.Attrs().Generation, nil
+}
+
 func init() {
 	env.Register(new(Env))
 
@@ -123,9 +234,175 @@ func (e *Env) ReadFile(ctx context.Context, path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
-// ReadFile reads the content of a file, caching the result on repeated reads if possible.
+// cacheIndexEntry records the size and last-use time of one cached file, for LRU eviction.
+type cacheIndexEntry struct {
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+func cacheIndexPath() string {
+	return filepath.Join(*saxCacheDir, "index.json")
+}
+
+func loadCacheIndex() map[string]cacheIndexEntry {
+	idx := map[string]cacheIndexEntry{}
+	data, err := os.ReadFile(cacheIndexPath())
+	if err != nil {
+		return idx
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return map[string]cacheIndexEntry{}
+	}
+	return idx
+}
+
+func saveCacheIndex(idx map[string]cacheIndexEntry) {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		log.Errorf("error marshaling cache index: %v", err)
+		return
+	}
+	if err := os.WriteFile(cacheIndexPath(), data, 0644); err != nil {
+		log.Errorf("error saving cache index: %v", err)
+	}
+}
+
+// cacheKey returns a filesystem-safe, content-addressed key for a GCS path.
+func cacheKey(path string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(path)))
+}
+
+func cacheFileName(path string, generation int64) string {
+	return fmt.Sprintf("%s.%d", cacheKey(path), generation)
+}
+
+// touchCache records name as recently used with the given size, evicting the
+// least-recently-used entries until the cache fits within --sax_cache_max_bytes.
+func touchCache(name string, size int64) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	idx := loadCacheIndex()
+	idx[name] = cacheIndexEntry{Size: size, LastUsed: time.Now()}
+
+	var total int64
+	names := make([]string, 0, len(idx))
+	for n, e := range idx {
+		total += e.Size
+		names = append(names, n)
+	}
+	if total > *saxCacheMaxBytes {
+		sort.Slice(names, func(i, j int) bool { return idx[names[i]].LastUsed.Before(idx[names[j]].LastUsed) })
+		for _, n := range names {
+			if total <= *saxCacheMaxBytes || n == name {
+				continue
+			}
+			if err := os.Remove(filepath.Join(*saxCacheDir, n)); err != nil && !os.IsNotExist(err) {
+				log.Errorf("error evicting cache file %v: %v", n, err)
+			}
+			total -= idx[n].Size
+			delete(idx, n)
+		}
+	}
+	saveCacheIndex(idx)
+}
+
+// pruneOldGenerations removes cached files for path other than keep, since only the latest
+// generation of a path is ever read again.
+func pruneOldGenerations(path, keep string) {
+	prefix := cacheKey(path) + "."
+	entries, err := os.ReadDir(*saxCacheDir)
+	if err != nil {
+		return
+	}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+	idx := loadCacheIndex()
+	changed := false
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == keep || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(*saxCacheDir, name)); err != nil && !os.IsNotExist(err) {
+			log.Errorf("error removing stale cache file %v: %v", name, err)
+			continue
+		}
+		delete(idx, name)
+		changed = true
+	}
+	if changed {
+		saveCacheIndex(idx)
+	}
+}
+
+// ReadCachedFile reads the content of a file, caching the result on repeated reads if possible.
+//
+// For gs:// paths, the cache is keyed by the object's generation under --sax_cache_dir: a cheap
+// Attrs() call checks whether a cached copy is still current before falling back to a full
+// download, stale generations for the same path are pruned, and the cache is bounded to
+// --sax_cache_max_bytes with LRU eviction. Concurrent reads of the same path are singleflighted
+// so a stampede only issues one GCS read.
 func (e *Env) ReadCachedFile(ctx context.Context, path string) ([]byte, error) {
-	return e.ReadFile(ctx, path)
+	if !strings.HasPrefix(path, gcsPathPrefix) {
+		return e.ReadFile(ctx, path)
+	}
+
+	v, err, _ := cacheGroup.Do(path, func() (any, error) {
+		_, object, err := gcsBucketAndObject(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		attrs, err := object.Attrs(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching attrs for %v: %w", path, err)
+		}
+		name := cacheFileName(path, attrs.Generation)
+		cachePath := filepath.Join(*saxCacheDir, name)
+
+		if data, err := os.ReadFile(cachePath); err == nil {
+			atomic.AddInt64(&cacheStats.hits, 1)
+			touchCache(name, int64(len(data)))
+			return data, nil
+		}
+		atomic.AddInt64(&cacheStats.misses, 1)
+
+		data, err := e.ReadFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(*saxCacheDir, 0777); err != nil {
+			log.Errorf("error creating cache dir %v, not caching: %v", *saxCacheDir, err)
+			return data, nil
+		}
+		if err := e.WriteFileAtomically(ctx, cachePath, data); err != nil {
+			log.Errorf("error writing cache file %v, not caching: %v", cachePath, err)
+			return data, nil
+		}
+		touchCache(name, int64(len(data)))
+		pruneOldGenerations(path, name)
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// CacheStats reports ReadCachedFile cache effectiveness counters.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Stats returns the current cache hit/miss counters, so operators can observe cache
+// effectiveness.
+func (e *Env) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&cacheStats.hits),
+		Misses: atomic.LoadInt64(&cacheStats.misses),
+	}
 }
 
 // WriteFile writes the content of a file.
@@ -301,26 +578,380 @@ func (e *Env) DirExists(ctx context.Context, path string) (bool, error) {
 	return false, err
 }
 
+// watchPollMinInterval, watchPollMaxInterval, and watchPollFastWindow bound the adaptive
+// backoff used when polling a GCS object for changes: polling stays fast for watchPollFastWindow
+// after the last observed change, then backs off exponentially up to watchPollMaxInterval.
+const (
+	watchPollMinInterval = 1 * time.Second
+	watchPollMaxInterval = 30 * time.Second
+	watchPollFastWindow  = 10 * time.Second
+)
+
 // Watch watches for content changes in a file and sends the new content on the returned channel.
+// The channel closes when ctx is cancelled.
+//
+// For gs:// paths, Watch subscribes to the Pub/Sub topic named by --gcs_notification_topic,
+// which GCS publishes object-change notifications to, filtering by the changed object's ID;
+// absent that flag it falls back to polling the object's Attrs with adaptive backoff. Local
+// paths are watched with fsnotify.
 func (e *Env) Watch(ctx context.Context, path string) (<-chan []byte, error) {
-	// Return a no-op channel because we don't support watching address changes yet.
-	return make(<-chan []byte), nil
+	if strings.HasPrefix(path, gcsPathPrefix) {
+		if *gcsNotificationTopic != "" {
+			return e.watchGCSPubSub(ctx, path)
+		}
+		return e.watchGCSPoll(ctx, path)
+	}
+	return e.watchLocal(ctx, path)
+}
+
+// nextPollInterval computes watchGCSPoll's next backoff interval given whether this poll
+// observed a generation change and how long it's been since the last observed change: a change
+// resets polling to watchPollMinInterval, and idling past watchPollFastWindow doubles the
+// interval up to watchPollMaxInterval. Split out from watchGCSPoll so the backoff math can be
+// unit tested without a real GCS client.
+func nextPollInterval(interval time.Duration, changed bool, sinceLastChange time.Duration) time.Duration {
+	if changed {
+		return watchPollMinInterval
+	}
+	if sinceLastChange > watchPollFastWindow {
+		interval *= 2
+		if interval > watchPollMaxInterval {
+			interval = watchPollMaxInterval
+		}
+	}
+	return interval
+}
+
+// watchGCSPoll implements Watch for gs:// paths when no Pub/Sub topic is configured.
+func (e *Env) watchGCSPoll(ctx context.Context, path string) (<-chan []byte, error) {
+	_, object, err := gcsBucketAndObject(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		var lastGeneration int64
+		interval := watchPollMinInterval
+		lastChange := time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+			attrs, err := object.Attrs(ctx)
+			if err != nil {
+				log.Errorf("error polling %v, retrying: %v", path, err)
+				continue
+			}
+			changed := attrs.Generation != lastGeneration
+			interval = nextPollInterval(interval, changed, time.Since(lastChange))
+			if !changed {
+				continue
+			}
+			lastGeneration = attrs.Generation
+			lastChange = time.Now()
+			data, err := e.ReadFile(ctx, path)
+			if err != nil {
+				log.Errorf("error reading changed file %v: %v", path, err)
+				continue
+			}
+			select {
+			case ch <- data:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// getPubSubClient returns the package-wide Pub/Sub client, building it once on first use.
+func getPubSubClient(ctx context.Context) (*pubsub.Client, error) {
+	pubsubClientOnce.Do(func() {
+		pubsubClient, pubsubClientErr = pubsub.NewClient(ctx, projectID)
+	})
+	return pubsubClient, pubsubClientErr
+}
+
+// watchGCSPubSub implements Watch for gs:// paths via GCS object-change notifications.
+func (e *Env) watchGCSPubSub(ctx context.Context, path string) (<-chan []byte, error) {
+	if _, _, err := gcsBucketAndObject(ctx, path); err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimPrefix(path, gcsPathPrefix)
+	_, objectName, _ := strings.Cut(trimmed, "/")
+
+	pubsubClient, err := getPubSubClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Pub/Sub client: %w", err)
+	}
+	sub, err := pubsubClient.CreateSubscription(ctx, fmt.Sprintf("sax-watch-%s", newHolderID()), pubsub.SubscriptionConfig{
+		Topic: pubsubClient.Topic(*gcsNotificationTopic),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating Pub/Sub subscription on topic %v: %w", *gcsNotificationTopic, err)
+	}
+
+	// Notifications are coalesced through a 1-deep trigger channel: a burst of messages about
+	// the same object results in a single re-read rather than one per message.
+	trigger := make(chan struct{}, 1)
+	go func() {
+		defer sub.Delete(context.Background())
+		if err := sub.Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
+			msg.Ack()
+			if msg.Attributes["objectId"] == objectName {
+				select {
+				case trigger <- struct{}{}:
+				default:
+				}
+			}
+		}); err != nil && ctx.Err() == nil {
+			log.Errorf("Pub/Sub receive loop for %v ended: %v", path, err)
+		}
+	}()
+
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-trigger:
+				time.Sleep(200 * time.Millisecond)
+				drainTrigger(trigger)
+				data, err := e.ReadFile(ctx, path)
+				if err != nil {
+					log.Errorf("error reading changed file %v: %v", path, err)
+					continue
+				}
+				select {
+				case ch <- data:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// drainTrigger discards any pending sends on trigger, coalescing a burst of notifications about
+// the same object into the single re-read already underway. Split out from watchGCSPubSub so
+// the coalescing behavior can be unit tested without a real Pub/Sub subscription.
+func drainTrigger(trigger <-chan struct{}) {
+	for {
+		select {
+		case <-trigger:
+		default:
+			return
+		}
+	}
+}
+
+// watchLocal implements Watch for local paths using fsnotify.
+func (e *Env) watchLocal(ctx context.Context, path string) (<-chan []byte, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					log.Errorf("error reading changed file %v: %v", path, err)
+					continue
+				}
+				select {
+				case ch <- data:
+				case <-ctx.Done():
+					return
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Errorf("fsnotify error watching %v: %v", path, err)
+			}
+		}
+	}()
+	return ch, nil
 }
 
 // Lead blocks until it acquires exclusive access to a file. The caller should arrange calling
 // close() on the returned channel to release the exclusive lock.
+//
+// On GCS paths, the lock is a real cross-process lock implemented with object generation
+// preconditions: readLease/writeLease implement compare-and-swap against the lock object, and
+// a background goroutine periodically refreshes the lease until the caller releases it or ctx
+// is cancelled. On local paths, flock(2) preserves the same exclusive-access semantics.
 func (e *Env) Lead(ctx context.Context, path string) (chan<- struct{}, error) {
-	// We don't support cross-process, file lock-based leader election yet.
-	// This in-process implementation makes the unit test pass.
-	muLeader.Lock()
+	if strings.HasPrefix(path, gcsPathPrefix) {
+		return e.leadGCS(ctx, path)
+	}
+	return e.leadLocal(ctx, path)
+}
+
+// leaseAction describes whether an acquisition attempt should write a new lease and with what
+// preconditions, derived from the current state of the lock object. Split out from leadGCS so
+// the acquire/back-off decision can be unit tested without a real GCS client.
+type leaseAction struct {
+	acquire        bool
+	wantGeneration int64 // precondition for writeLease; 0 means the object must not exist
+	epoch          int64 // epoch to write, if acquire is true
+}
+
+// decideLeaseAction inspects the result of reading a lock object (see readLease) and decides
+// what, if anything, this acquisition attempt should write.
+func decideLeaseAction(rec *leaseRecord, gen int64, readErr error, now time.Time) leaseAction {
+	switch {
+	case readErr == storage.ErrObjectNotExist:
+		return leaseAction{acquire: true, wantGeneration: 0, epoch: 1}
+	case readErr != nil:
+		return leaseAction{}
+	case now.After(rec.Deadline):
+		return leaseAction{acquire: true, wantGeneration: gen, epoch: rec.Epoch + 1}
+	default:
+		return leaseAction{}
+	}
+}
+
+// leadGCS implements Lead for gs:// paths. See Lead for the locking scheme.
+func (e *Env) leadGCS(ctx context.Context, path string) (chan<- struct{}, error) {
+	_, object, err := gcsBucketAndObject(ctx, lockPath(path))
+	if err != nil {
+		return nil, err
+	}
+	holder := newHolderID()
+
+	var generation, epoch int64
+	for generation == 0 {
+		rec, gen, readErr := readLease(ctx, object)
+		action := decideLeaseAction(rec, gen, readErr, time.Now())
+		if readErr != nil && readErr != storage.ErrObjectNotExist {
+			log.Errorf("error reading lock %v, retrying: %v", path, readErr)
+		}
+		if action.acquire {
+			if newGen, werr := writeLease(ctx, object, leaseRecord{Holder: holder, Deadline: time.Now().Add(leaseDuration), Epoch: action.epoch}, action.wantGeneration); werr == nil {
+				generation, epoch = newGen, action.epoch
+			}
+		}
+		if generation != 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(leaseRetryInterval):
+		}
+	}
+
 	closer := make(chan struct{})
 	go func() {
-		<-closer
-		muLeader.Unlock()
+		ticker := time.NewTicker(leaseRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closer:
+				if err := object.If(storage.Conditions{GenerationMatch: generation}).Delete(ctx); err != nil {
+					log.Errorf("error releasing lock %v: %v", path, err)
+				}
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// Carry the epoch forward unchanged: it counts acquisitions, not refreshes.
+				newGen, err := writeLease(ctx, object, leaseRecord{Holder: holder, Deadline: time.Now().Add(leaseDuration), Epoch: epoch}, generation)
+				if err != nil {
+					log.Errorf("error refreshing lease on %v, lock may expire: %v", path, err)
+					continue
+				}
+				generation = newGen
+			}
+		}
 	}()
 	return closer, nil
 }
 
+// leadLocal implements Lead for local paths using flock(2). flock already blocks a second
+// acquirer of the same path until the first releases it, so no additional in-process mutex is
+// needed to preserve the exclusive semantics this package offered before cross-process GCS
+// locking existed; a single package-global mutex here would instead serialize Lead calls on
+// unrelated paths against each other for no correctness benefit.
+func (e *Env) leadLocal(ctx context.Context, path string) (chan<- struct{}, error) {
+	f, err := os.OpenFile(lockPath(path), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	f.Truncate(0)
+	f.WriteAt([]byte(newHolderID()), 0)
+
+	closer := make(chan struct{})
+	go func() {
+		select {
+		case <-closer:
+		case <-ctx.Done():
+		}
+		f.Truncate(0)
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}()
+	return closer, nil
+}
+
+// CurrentHolder returns the identifier of whoever currently holds the lock at path, for
+// diagnostics. It does not itself acquire the lock.
+func (e *Env) CurrentHolder(ctx context.Context, path string) (string, error) {
+	if strings.HasPrefix(path, gcsPathPrefix) {
+		_, object, err := gcsBucketAndObject(ctx, lockPath(path))
+		if err != nil {
+			return "", err
+		}
+		rec, _, err := readLease(ctx, object)
+		if err != nil {
+			return "", err
+		}
+		if time.Now().After(rec.Deadline) {
+			return "", nil
+		}
+		return rec.Holder, nil
+	}
+
+	data, err := os.ReadFile(lockPath(path))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // PickUnusedPort picks an unused port.
 func (e *Env) PickUnusedPort() (port int, err error) {
 	listener, err := net.Listen("tcp", ":0")
@@ -331,9 +962,44 @@ func (e *Env) PickUnusedPort() (port int, err error) {
 	return listener.Addr().(*net.TCPAddr).Port, nil
 }
 
-// DialContext establishes a gRPC connection to the target.
+// clientTransportCredentials builds the TLS credentials used to dial Sax servers, trusting
+// --sax_tls_ca if set and the system cert pool otherwise.
+func clientTransportCredentials() (credentials.TransportCredentials, error) {
+	if *saxTLSCA == "" {
+		return credentials.NewClientTLSFromCert(nil, ""), nil
+	}
+	pool := x509.NewCertPool()
+	pem, err := os.ReadFile(*saxTLSCA)
+	if err != nil {
+		return nil, fmt.Errorf("error reading --sax_tls_ca %v: %w", *saxTLSCA, err)
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in --sax_tls_ca %v", *saxTLSCA)
+	}
+	return credentials.NewClientTLSFromCert(pool, ""), nil
+}
+
+// DialContext establishes a gRPC connection to the target, authenticated with the caller's
+// Google Application Default Credentials over TLS. Pass --sax_allow_insecure for local testing
+// against a server started the same way.
 func (e *Env) DialContext(ctx context.Context, target string) (*grpc.ClientConn, error) {
-	return grpc.DialContext(ctx, target, grpc.WithInsecure())
+	if *saxAllowInsecure {
+		return grpc.DialContext(ctx, target, grpc.WithInsecure())
+	}
+
+	transportCreds, err := clientTransportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(transportCreds)}
+
+	creds, err := google.FindDefaultCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error finding Google Application Default Credentials, pass --sax_allow_insecure to skip: %w", err)
+	}
+	opts = append(opts, grpc.WithPerRPCCredentials(oauth.TokenSource{TokenSource: creds.TokenSource}))
+
+	return grpc.DialContext(ctx, target, opts...)
 }
 
 // RequiredACLNamePrefix returns the string required to prefix all ACL names.
@@ -351,17 +1017,179 @@ func (s *Server) GRPCServer() *grpc.Server {
 	return s.Server
 }
 
+// callerIdentityKey is the context key identityInterceptor stores the caller's identity under.
+type callerIdentityKey struct{}
+
+var (
+	tokeninfoServiceOnce sync.Once
+	tokeninfoService     *oauth2api.Service
+	tokeninfoServiceErr  error
+
+	identityCacheMu sync.Mutex
+	identityCache   = map[string]identityCacheEntry{}
+)
+
+// identityCacheEntry is a validated token's resolved identity, cached until expiry so
+// callerIdentity doesn't make a tokeninfo round trip on every RPC.
+type identityCacheEntry struct {
+	identity string
+	expiry   time.Time
+}
+
+// getTokeninfoService returns the package-wide tokeninfo client, building it once on first use.
+func getTokeninfoService(ctx context.Context) (*oauth2api.Service, error) {
+	tokeninfoServiceOnce.Do(func() {
+		tokeninfoService, tokeninfoServiceErr = oauth2api.NewService(ctx, option.WithoutAuthentication())
+	})
+	return tokeninfoService, tokeninfoServiceErr
+}
+
+// tokenCacheKey hashes token so the identity cache never retains a live, reusable credential
+// in memory, only a non-reversible lookup key for it.
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return string(sum[:])
+}
+
+// hasScope reports whether required appears in the space-separated scope list granted, the
+// format Google's tokeninfo endpoint reports scopes in.
+func hasScope(granted, required string) bool {
+	for _, s := range strings.Fields(granted) {
+		if s == required {
+			return true
+		}
+	}
+	return false
+}
+
+// callerIdentity resolves the caller's bearer token from incoming gRPC metadata to a stable
+// principal (their account email) by validating it against Google's tokeninfo endpoint,
+// checking that the token was issued to --sax_oauth_audience with --sax_oauth_scope (so a
+// token minted for an unrelated Google API can't be replayed as proof of identity here; each
+// check is skipped when its flag is empty, as it is by default), and
+// caching the result for the token's own remaining lifetime. The raw token itself is never
+// used as an identity or surfaced in errors or logs, since it's a live, reusable credential;
+// only a hash of it is kept, as the cache key. The returned email is expected to match one of
+// the ACL strings published alongside a model.
+func callerIdentity(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("no caller metadata in context: %w", errors.ErrPermissionDenied)
+	}
+	var token string
+	for _, v := range md.Get("authorization") {
+		token = strings.TrimPrefix(v, "Bearer ")
+	}
+	if token == "" {
+		return "", fmt.Errorf("no bearer token in caller metadata: %w", errors.ErrPermissionDenied)
+	}
+	key := tokenCacheKey(token)
+
+	identityCacheMu.Lock()
+	entry, cached := identityCache[key]
+	identityCacheMu.Unlock()
+	if cached && time.Now().Before(entry.expiry) {
+		return entry.identity, nil
+	}
+
+	oauthService, err := getTokeninfoService(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error creating tokeninfo client: %w", err)
+	}
+	info, err := oauthService.Tokeninfo().AccessToken(token).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("caller token failed validation: %w", errors.ErrPermissionDenied)
+	}
+	if *saxOAuthAudience != "" && info.Audience != *saxOAuthAudience && info.IssuedTo != *saxOAuthAudience {
+		return "", fmt.Errorf("caller token is not issued for this service: %w", errors.ErrPermissionDenied)
+	}
+	if *saxOAuthScope != "" && !hasScope(info.Scope, *saxOAuthScope) {
+		return "", fmt.Errorf("caller token is missing required scope: %w", errors.ErrPermissionDenied)
+	}
+
+	ttl := time.Duration(info.ExpiresIn) * time.Second
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	identityCacheMu.Lock()
+	identityCache[key] = identityCacheEntry{identity: info.Email, expiry: time.Now().Add(ttl)}
+	for k, e := range identityCache {
+		if time.Now().After(e.expiry) {
+			delete(identityCache, k)
+		}
+	}
+	identityCacheMu.Unlock()
+
+	return info.Email, nil
+}
+
+// identityInterceptor stashes the caller's identity, resolved from their OAuth token, into the
+// context so CheckACLs can consult it.
+func identityInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	identity, err := callerIdentity(ctx)
+	if err != nil {
+		log.Warningf("error resolving caller identity for %v: %v", info.FullMethod, err)
+	}
+	return handler(context.WithValue(ctx, callerIdentityKey{}, identity), req)
+}
+
+// identityServerStream wraps a grpc.ServerStream to carry the caller's identity in its Context,
+// the streaming-RPC counterpart of identityInterceptor.
+type identityServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream.
+func (s *identityServerStream) Context() context.Context { return s.ctx }
+
+// streamIdentityInterceptor stashes the caller's identity, resolved from their OAuth token, into
+// the stream's context so CheckACLs can consult it during streaming RPCs.
+func streamIdentityInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	identity, err := callerIdentity(ss.Context())
+	if err != nil {
+		log.Warningf("error resolving caller identity for %v: %v", info.FullMethod, err)
+	}
+	ctx := context.WithValue(ss.Context(), callerIdentityKey{}, identity)
+	return handler(srv, &identityServerStream{ServerStream: ss, ctx: ctx})
+}
+
 // CheckACLs returns nil iff the principal extracted from ctx passes an ACL check.
 func (s *Server) CheckACLs(ctx context.Context, acls []string) error {
 	if len(acls) == 0 {
 		return nil
 	}
-	return fmt.Errorf("ACL check is not supported: %w", errors.ErrUnimplemented)
+	identity, _ := ctx.Value(callerIdentityKey{}).(string)
+	if identity == "" {
+		return fmt.Errorf("caller identity could not be established: %w", errors.ErrPermissionDenied)
+	}
+	for _, acl := range acls {
+		if acl == identity {
+			return nil
+		}
+	}
+	return fmt.Errorf("caller %v is not in ACL %v: %w", identity, acls, errors.ErrPermissionDenied)
 }
 
-// NewServer creates a gRPC server.
+// NewServer creates a gRPC server, authenticated with --sax_tls_cert/--sax_tls_key and wired up
+// to extract caller identity for Server.CheckACLs. Pass --sax_allow_insecure for local testing.
 func (e *Env) NewServer() (env.Server, error) {
-	s := &Server{grpc.NewServer()}
+	var opts []grpc.ServerOption
+	switch {
+	case *saxTLSCert != "" && *saxTLSKey != "":
+		transportCreds, err := credentials.NewServerTLSFromFile(*saxTLSCert, *saxTLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading --sax_tls_cert/--sax_tls_key: %w", err)
+		}
+		opts = append(opts, grpc.Creds(transportCreds))
+	case *saxAllowInsecure:
+		// No transport credentials; plaintext is allowed for local testing.
+	default:
+		return nil, fmt.Errorf("--sax_tls_cert and --sax_tls_key are required unless --sax_allow_insecure is set: %w", errors.ErrFailedPrecondition)
+	}
+	opts = append(opts, grpc.UnaryInterceptor(identityInterceptor), grpc.StreamInterceptor(streamIdentityInterceptor))
+
+	s := &Server{grpc.NewServer(opts...)}
 	reflection.Register(s.GRPCServer())
 	return s, nil
 }
@@ -0,0 +1,65 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+func TestDecideLeaseAction(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name       string
+		rec        *leaseRecord
+		gen        int64
+		readErr    error
+		wantAction leaseAction
+	}{
+		{
+			name:       "lock object absent",
+			readErr:    storage.ErrObjectNotExist,
+			wantAction: leaseAction{acquire: true, wantGeneration: 0, epoch: 1},
+		},
+		{
+			name:       "transient read error",
+			readErr:    context.DeadlineExceeded,
+			wantAction: leaseAction{},
+		},
+		{
+			name:       "lease expired",
+			rec:        &leaseRecord{Holder: "other", Deadline: now.Add(-time.Second), Epoch: 3},
+			gen:        42,
+			wantAction: leaseAction{acquire: true, wantGeneration: 42, epoch: 4},
+		},
+		{
+			name:       "lease still held by someone else",
+			rec:        &leaseRecord{Holder: "other", Deadline: now.Add(time.Minute), Epoch: 3},
+			gen:        42,
+			wantAction: leaseAction{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := decideLeaseAction(c.rec, c.gen, c.readErr, now)
+			if got != c.wantAction {
+				t.Errorf("decideLeaseAction() = %+v, want %+v", got, c.wantAction)
+			}
+		})
+	}
+}
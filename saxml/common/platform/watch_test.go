@@ -0,0 +1,90 @@
+/*  */ // Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextPollInterval(t *testing.T) {
+	cases := []struct {
+		name            string
+		interval        time.Duration
+		changed         bool
+		sinceLastChange time.Duration
+		want            time.Duration
+	}{
+		{
+			name:            "change resets to min interval",
+			interval:        16 * time.Second,
+			changed:         true,
+			sinceLastChange: time.Hour,
+			want:            watchPollMinInterval,
+		},
+		{
+			name:            "still within fast window holds interval",
+			interval:        watchPollMinInterval,
+			changed:         false,
+			sinceLastChange: watchPollFastWindow - time.Second,
+			want:            watchPollMinInterval,
+		},
+		{
+			name:            "idle past fast window doubles interval",
+			interval:        4 * time.Second,
+			changed:         false,
+			sinceLastChange: watchPollFastWindow + time.Second,
+			want:            8 * time.Second,
+		},
+		{
+			name:            "doubling caps at max interval",
+			interval:        watchPollMaxInterval,
+			changed:         false,
+			sinceLastChange: watchPollFastWindow + time.Second,
+			want:            watchPollMaxInterval,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := nextPollInterval(c.interval, c.changed, c.sinceLastChange); got != c.want {
+				t.Errorf("nextPollInterval(%v, %v, %v) = %v, want %v", c.interval, c.changed, c.sinceLastChange, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDrainTrigger(t *testing.T) {
+	trigger := make(chan struct{}, 3)
+	trigger <- struct{}{}
+	trigger <- struct{}{}
+	trigger <- struct{}{}
+
+	done := make(chan struct{})
+	go func() {
+		drainTrigger(trigger)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drainTrigger did not return after the channel emptied")
+	}
+
+	select {
+	case <-trigger:
+		t.Fatal("drainTrigger left a pending value on the channel")
+	default:
+	}
+}
@@ -1,92 +1,256 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-    "fmt"
-    "context"
-    "os"
 
 	"github.com/myzhan/boomer"
-    "saxml/client/go/sax"
-    "saxml/common/platform/env"
-    _ "saxml/common/platform/register" //registers a platform
+	"saxml/client/go/sax"
+	"saxml/common/platform/env"
+	_ "saxml/common/platform/register" //registers a platform
 )
 
-func foo(){
-    start := time.Now()
-    time.Sleep(100 * time.Millisecond)
-    elapsed := time.Since(start)
-    /*
-    Report your test result as a success, if you write it in locust, it will looks like this
-    events.request_success.fire(request_type="http", name="foo", response_time=100, response_length=10)
-    */
-    globalBoomer.RecordSuccess("http", "foo", elapsed.Nanoseconds()/int64(time.Millisecond), int64(10))
+var (
+	mode = flag.String("mode", "stream", `Load generation mode: "stream" issues Generate calls through the `+
+		`streaming client path (see sax.LanguageModel.GenerateStream's doc comment for its current limitations); `+
+		`"blocking" reproduces the original single blocking Generate call per request`)
+	modelAddrs = flag.String("model_addrs", "/sax/test/llama7bfp16tpuv5e", "Comma-separated sax model addresses "+
+		`to send traffic to, each optionally suffixed with ":<weight>" (default weight 1) for weighted round `+
+		`robin across shards, e.g. "/sax/a/m1:2,/sax/a/m2:1"`)
+	datasetFile = flag.String("dataset_file", "", "Path to a prompt dataset: a HuggingFace-style JSONL file "+
+		"(one JSON object per line, see --prompt_field) or a plain text file with one prompt per line. If "+
+		"empty, a single built-in prompt is used")
+	promptField = flag.String("prompt_field", "prompt", "JSON field holding the prompt text in each line of "+
+		"--dataset_file, when it's a JSONL file")
+	saxRoot    = flag.String("sax_root", "gs://jk-saxml-admin-bucket/sax-root", "SAX_ROOT to use if not already set in the environment")
+	numClients = flag.Int("num_clients", 1, "Number of concurrent boomer clients")
+	spawnRate  = flag.Float64("spawn_rate", 1, "Boomer client spawn rate")
+)
+
+// endpoint is one sax model address in the load test's target set.
+type endpoint struct {
+	addr   string
+	weight int
+}
+
+// parseEndpoints parses a comma-separated "addr[:weight],..." list as passed to --model_addrs.
+func parseEndpoints(s string) ([]endpoint, error) {
+	var endpoints []endpoint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		addr, weightStr, hasWeight := strings.Cut(part, ":")
+		weight := 1
+		if hasWeight {
+			w, err := strconv.Atoi(weightStr)
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("invalid weight in %q: %v", part, err)
+			}
+			weight = w
+		}
+		endpoints = append(endpoints, endpoint{addr: addr, weight: weight})
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("--model_addrs must name at least one model address")
+	}
+	return endpoints, nil
+}
+
+// lmPool hands out *sax.LanguageModel handles in weighted round-robin order, so a single boomer
+// run can spread load across multiple (e.g. sharded) model deployments.
+type lmPool struct {
+	lms []*sax.LanguageModel // each entry repeated once per unit of its endpoint's weight
+	next uint64
+}
+
+func newLMPool(ctx context.Context, endpoints []endpoint) (*lmPool, error) {
+	pool := &lmPool{}
+	for _, ep := range endpoints {
+		model, err := sax.Open(ep.addr)
+		if err != nil {
+			return nil, fmt.Errorf("error opening model %v: %w", ep.addr, err)
+		}
+		lm := model.LM()
+		for i := 0; i < ep.weight; i++ {
+			pool.lms = append(pool.lms, lm)
+		}
+	}
+	return pool, nil
+}
+
+func (p *lmPool) pick() *sax.LanguageModel {
+	i := atomic.AddUint64(&p.next, 1)
+	return p.lms[i%uint64(len(p.lms))]
+}
+
+// promptSource cycles through a dataset of prompts, so request shape reflects realistic input
+// lengths instead of a single canned prompt.
+type promptSource struct {
+	mu      sync.Mutex
+	prompts []string
+	next    int
+}
+
+// loadPrompts reads --dataset_file into a promptSource. A ".jsonl" file is read one JSON object
+// per line, pulling the prompt out of the --prompt_field key; anything else is treated as a
+// plain text file with one prompt per line. An empty path falls back to a single built-in prompt.
+func loadPrompts(path, field string) (*promptSource, error) {
+	if path == "" {
+		return &promptSource{prompts: []string{"Who are you?"}}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening dataset file %v: %w", path, err)
+	}
+	defer f.Close()
+
+	var prompts []string
+	scanner := bufio.NewScanner(f)
+	isJSONL := strings.HasSuffix(path, ".jsonl")
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !isJSONL {
+			prompts = append(prompts, line)
+			continue
+		}
+		var row map[string]any
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			log.Printf("skipping malformed JSONL row: %v", err)
+			continue
+		}
+		if prompt, ok := row[field].(string); ok {
+			prompts = append(prompts, prompt)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading dataset file %v: %w", path, err)
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("dataset file %v contained no prompts", path)
+	}
+	return &promptSource{prompts: prompts}, nil
 }
 
-func lm_generate() {
-    
-    query := "Who are you ?"
-    start := time.Now()
-    response, err := globalLm.Generate(ctx, query)
-    //time.Sleep(1000 * time.Millisecond)
-    elapsed := time.Since(start)
-    if err == nil {
-        /*
-        Report your test result as a success, if you write it in locust, it will looks like this
-        events.request_success.fire(request_type="http", name="foo", response_time=100, response_length=10)
-        */
-        response_len := 0
-        for _, generate := range response {
-           response_len += len(generate.Text)
-        } 
-
-        globalBoomer.RecordSuccess("saxml.client", "lm.Generate", elapsed.Nanoseconds()/int64(time.Millisecond), int64(response_len)) 
-    } else {
-
-        globalBoomer.RecordFailure("saxml.client", "lm.Generate", elapsed.Nanoseconds()/int64(time.Millisecond), err.Error())  
-    }
-
-    
+func (p *promptSource) pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prompt := p.prompts[p.next%len(p.prompts)]
+	p.next++
+	return prompt
 }
 
-var globalBoomer *boomer.Boomer
-var globalLm *sax.LanguageModel
-var ctx context.Context
-
-func main(){
-    log.SetFlags(log.LstdFlags | log.Lshortfile)
-
-//    task1 := &boomer.Task{
-//        Name: "foo",
-//        // The weight is used to distribute goroutines over multiple tasks.
-//        Weight: 10,
-//        Fn: foo,
-//    }
-
-    task2 := &boomer.Task{
-        Name: "lm.Generate",
-        // The weight is used to distribute goroutines over multiple tasks.
-        Weight: 10,
-        Fn: lm_generate,
-    }
-
-    model, err := sax.Open("/sax/test/llama7bfp16tpuv5e")
-    if err != nil {
-        fmt.Print("Error opening the model")
-    } else {
-        globalLm  = model.LM()
-    }
-
-    os.Setenv("SAX_ROOT", "gs://jk-saxml-admin-bucket/sax-root")
-    
-    ctx = context.Background()
-    env.Get().Init(ctx)
-
-    numClients := 1
-	spawnRate := float64(1)
-	globalBoomer = boomer.NewStandaloneBoomer(numClients, spawnRate)
-	globalBoomer.AddOutput(boomer.NewConsoleOutput()) 
-    // Start tasks
-    globalBoomer.Run(task2)
+var (
+	globalBoomer *boomer.Boomer
+	lms          *lmPool
+	prompts      *promptSource
+	ctx          context.Context
+)
+
+// lmGenerateBlocking reproduces the original load task: one blocking LM.Generate call per
+// goroutine, reporting only overall latency and response length. Selected with --mode=blocking
+// for compatibility with older benchmark runs.
+func lmGenerateBlocking() {
+	lm := lms.pick()
+	prompt := prompts.pick()
+
+	start := time.Now()
+	response, err := lm.Generate(ctx, prompt)
+	elapsed := time.Since(start)
+	if err != nil {
+		globalBoomer.RecordFailure("saxml.client", "lm.Generate", elapsed.Milliseconds(), err.Error())
+		return
+	}
+
+	responseLen := 0
+	for _, generate := range response {
+		responseLen += len(generate.Text)
+	}
+	globalBoomer.RecordSuccess("saxml.client", "lm.Generate", elapsed.Milliseconds(), int64(responseLen))
+}
+
+// lmGenerateStream issues a Generate call through the streaming client path and reports overall
+// latency, the same metric lmGenerateBlocking reports. It does not report time-to-first-token or
+// inter-token latency: GenerateStream currently replays Generate's N-best results onto a channel
+// rather than real per-token chunks from the server (see its doc comment), so those numbers
+// would describe the blocking call's latency relabeled, not anything the streaming path actually
+// makes visible. This task exists to exercise the streaming client code path; recompute
+// TTFT/TPOT here once GenerateStream is backed by a real streaming RPC.
+func lmGenerateStream() {
+	lm := lms.pick()
+	prompt := prompts.pick()
+
+	start := time.Now()
+	stream, err := lm.GenerateStream(ctx, prompt)
+	if err != nil {
+		globalBoomer.RecordFailure("saxml.client", "lm.Generate", time.Since(start).Milliseconds(), err.Error())
+		return
+	}
+
+	responseLen := 0
+	for item := range stream {
+		if item.Err != nil {
+			globalBoomer.RecordFailure("saxml.client", "lm.Generate", time.Since(start).Milliseconds(), item.Err.Error())
+			return
+		}
+		responseLen += len(item.Text)
+	}
+	elapsed := time.Since(start)
+
+	globalBoomer.RecordSuccess("saxml.client", "lm.Generate", elapsed.Milliseconds(), int64(responseLen))
+}
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	flag.Parse()
+
+	if os.Getenv("SAX_ROOT") == "" {
+		os.Setenv("SAX_ROOT", *saxRoot)
+	}
+
+	ctx = context.Background()
+	env.Get().Init(ctx)
+
+	endpoints, err := parseEndpoints(*modelAddrs)
+	if err != nil {
+		log.Fatalf("error parsing --model_addrs: %v", err)
+	}
+	lms, err = newLMPool(ctx, endpoints)
+	if err != nil {
+		log.Fatalf("error opening models: %v", err)
+	}
+	prompts, err = loadPrompts(*datasetFile, *promptField)
+	if err != nil {
+		log.Fatalf("error loading prompts: %v", err)
+	}
+
+	generateFn := lmGenerateStream
+	if *mode == "blocking" {
+		generateFn = lmGenerateBlocking
+	}
+	task := &boomer.Task{
+		Name: "lm.Generate",
+		// The weight is used to distribute goroutines over multiple tasks.
+		Weight: 10,
+		Fn:     generateFn,
+	}
+
+	globalBoomer = boomer.NewStandaloneBoomer(*numClients, *spawnRate)
+	globalBoomer.AddOutput(boomer.NewConsoleOutput())
+	globalBoomer.Run(task)
 }